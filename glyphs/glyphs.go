@@ -0,0 +1,155 @@
+// Package glyphs defines pluggable sets of glyphs ("A" variants) that the
+// generator samples from, including weighted sampling and loading
+// user-supplied sets from disk.
+package glyphs
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ovnanova/AAAAAAAAA/internal/sampling"
+)
+
+// Glyph is a single sampleable unit: a grapheme cluster plus its relative
+// sampling weight.
+type Glyph struct {
+	Text   string
+	Weight int
+}
+
+// GlyphSet holds a set of glyphs along with a precomputed cumulative-weight
+// table so Pick can sample in O(log n) instead of uniform O(1) indexing.
+type GlyphSet struct {
+	Name   string
+	glyphs []Glyph
+	cumSum []int
+	total  int
+}
+
+// New builds a GlyphSet from glyphs, defaulting any non-positive weight to 1.
+func New(name string, glyphs []Glyph) *GlyphSet {
+	cumSum := make([]int, len(glyphs))
+	total := 0
+	for i, g := range glyphs {
+		w := g.Weight
+		if w <= 0 {
+			w = 1
+		}
+		total += w
+		cumSum[i] = total
+	}
+	return &GlyphSet{Name: name, glyphs: glyphs, cumSum: cumSum, total: total}
+}
+
+// Len reports the number of distinct glyphs in the set.
+func (s *GlyphSet) Len() int { return len(s.glyphs) }
+
+// Pick samples one glyph, weighted, using a binary search over the
+// cumulative-weight table rather than a uniform rand.Intn(len).
+func (s *GlyphSet) Pick(r *rand.Rand) string {
+	if s.total == 0 {
+		return ""
+	}
+	target := r.Intn(s.total) + 1
+	i := sort.Search(len(s.cumSum), func(i int) bool { return s.cumSum[i] >= target })
+	return s.glyphs[i].Text
+}
+
+// SampleGlyphs returns the individual glyphs making up exactly
+// visible glyphs-worth of output, counted in grapheme clusters rather than
+// runes, so callers get "N visible A's" regardless of how many combining
+// marks or multi-rune clusters make up each glyph.
+func (s *GlyphSet) SampleGlyphs(r *rand.Rand, visible int) []string {
+	return sampling.Glyphs(r, visible, s.Pick)
+}
+
+// Sample builds a single string from SampleGlyphs.
+func (s *GlyphSet) Sample(r *rand.Rand, visible int) string {
+	return strings.Join(s.SampleGlyphs(r, visible), "")
+}
+
+// builtins holds the static sets shipped with the binary, keyed by -set
+// name. The procedural "zalgo" set lives in package zalgo instead, since it
+// generates glyphs on the fly rather than sampling from a fixed list.
+var builtins = map[string]*GlyphSet{
+	"fullwidth": New("fullwidth", []Glyph{
+		{Text: "Ａ"},
+	}),
+	"mathbb": New("mathbb", []Glyph{
+		{Text: "𝔸"}, {Text: "𝐀"}, {Text: "𝑨"}, {Text: "𝒜"}, {Text: "𝔄"},
+	}),
+	"boxed": New("boxed", []Glyph{
+		{Text: "░A░"}, {Text: "[A]"}, {Text: "🅰"}, {Text: "Ⓐ"},
+	}),
+	"combining-only": New("combining-only", []Glyph{
+		{Text: "Á"}, {Text: "À"}, {Text: "Â"}, {Text: "Ã"}, {Text: "Ä"}, {Text: "Å"},
+	}),
+}
+
+// Builtin returns the built-in set registered under name, if any.
+func Builtin(name string) (*GlyphSet, bool) {
+	s, ok := builtins[name]
+	return s, ok
+}
+
+// Names returns the names of all built-in sets, for usage/error messages.
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load reads a GlyphSet from a UTF-8 file: one glyph or grapheme cluster
+// per line, blank lines and lines starting with # ignored, with an
+// optional tab-separated weight ("glyph\tweight").
+func Load(path string) (*GlyphSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("glyphs: %w", err)
+	}
+	defer f.Close()
+
+	var glyphs []Glyph
+	sc := bufio.NewScanner(f)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "\t", 2)
+		text := parts[0]
+		if strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("glyphs: %s:%d: empty glyph", path, lineNo)
+		}
+		weight := 1
+		if len(parts) == 2 {
+			w, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+			if err != nil {
+				return nil, fmt.Errorf("glyphs: %s:%d: invalid weight %q", path, lineNo, parts[1])
+			}
+			weight = w
+		}
+		glyphs = append(glyphs, Glyph{Text: text, Weight: weight})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("glyphs: %w", err)
+	}
+	if len(glyphs) == 0 {
+		return nil, fmt.Errorf("glyphs: %s contains no glyphs", path)
+	}
+
+	return New(filepath.Base(path), glyphs), nil
+}
@@ -0,0 +1,63 @@
+package glyphs
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPickOnlyReturnsKnownGlyphs(t *testing.T) {
+	set := New("test", []Glyph{{Text: "a", Weight: 1}, {Text: "b", Weight: 1}})
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		got := set.Pick(r)
+		if got != "a" && got != "b" {
+			t.Fatalf("Pick returned unexpected glyph %q", got)
+		}
+	}
+}
+
+func TestPickRespectsWeights(t *testing.T) {
+	set := New("test", []Glyph{{Text: "common", Weight: 1000}, {Text: "rare", Weight: 1}})
+	r := rand.New(rand.NewSource(1))
+	var commonCount int
+	for i := 0; i < 1000; i++ {
+		if set.Pick(r) == "common" {
+			commonCount++
+		}
+	}
+	if commonCount < 900 {
+		t.Fatalf("expected heavily-weighted glyph to dominate, got %d/1000", commonCount)
+	}
+}
+
+func TestSampleCountsGraphemeClustersNotRunes(t *testing.T) {
+	// "é" as e + combining acute is two runes but one grapheme cluster.
+	set := New("test", []Glyph{{Text: "é"}})
+	r := rand.New(rand.NewSource(1))
+	out := set.Sample(r, 5)
+	if n := strings.Count(out, "e"); n != 5 {
+		t.Fatalf("expected 5 visible glyphs, got %d in %q", n, out)
+	}
+}
+
+func TestLoadRejectsEmptyGlyph(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "set.txt")
+	if err := os.WriteFile(path, []byte("\t5\n\t3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected Load to reject a line with an empty glyph, got nil error")
+	}
+}
+
+func TestBuiltinSetsArePopulated(t *testing.T) {
+	for _, name := range Names() {
+		set, ok := Builtin(name)
+		if !ok || set.Len() == 0 {
+			t.Fatalf("builtin set %q missing or empty", name)
+		}
+	}
+}
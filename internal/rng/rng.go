@@ -0,0 +1,62 @@
+// Package rng provides goroutine-safe random sources for the generator,
+// supporting both unpredictable and reproducible runs.
+package rng
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+)
+
+// lockedSource wraps a rand.Source with a mutex so a single *rand.Rand can
+// be shared safely across multiple producer goroutines.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// NewSource wraps src in a lockedSource and returns a *rand.Rand safe for
+// concurrent use.
+func NewSource(src rand.Source) *rand.Rand {
+	return rand.New(&lockedSource{src: src})
+}
+
+// NewCryptoSource returns a rand.Source seeded from crypto/rand, for
+// unpredictable output when no -seed is given.
+func NewCryptoSource() rand.Source {
+	return rand.NewSource(CryptoSeed())
+}
+
+// NewSeededSource returns a rand.Source seeded deterministically from
+// seed, so runs can be reproduced exactly.
+func NewSeededSource(seed int64) rand.Source {
+	return rand.NewSource(seed)
+}
+
+// CryptoSeed draws a fresh int64 seed from crypto/rand. Callers that need
+// to log or reproduce a run (e.g. to print "seed used" on shutdown) should
+// call this once and feed the result to NewSeededSource, rather than using
+// NewCryptoSource directly.
+func CryptoSeed() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		// crypto/rand failing means the OS entropy source is broken;
+		// fall back to a fixed value rather than leaving the source
+		// half-seeded.
+		return 1
+	}
+	return int64(binary.BigEndian.Uint64(b[:]))
+}
@@ -0,0 +1,23 @@
+// Package sampling holds the grapheme-cluster-aware sampling loop shared by
+// glyphs.GlyphSet and zalgo.Generator, so both types count "visible glyphs"
+// the same way without duplicating the loop.
+package sampling
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// Glyphs draws glyphs from pick until exactly visible glyphs-worth of
+// output has accumulated, counted in grapheme clusters rather than runes,
+// so callers get "N visible A's" regardless of how many combining marks or
+// multi-rune clusters make up each glyph.
+func Glyphs(r *rand.Rand, visible int, pick func(*rand.Rand) string) []string {
+	var picked []string
+	for uniseg.GraphemeClusterCount(strings.Join(picked, "")) < visible {
+		picked = append(picked, pick(r))
+	}
+	return picked
+}
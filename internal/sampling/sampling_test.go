@@ -0,0 +1,25 @@
+package sampling
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestGlyphsCountsGraphemeClustersNotRunes(t *testing.T) {
+	// "e" + combining acute (U+0301) is two runes but one grapheme cluster.
+	pick := func(r *rand.Rand) string { return "e\u0301" }
+	r := rand.New(rand.NewSource(1))
+	out := strings.Join(Glyphs(r, 5, pick), "")
+	if n := strings.Count(out, "e"); n != 5 {
+		t.Fatalf("expected 5 visible glyphs, got %d in %q", n, out)
+	}
+}
+
+func TestGlyphsStopsAsSoonAsVisibleIsReached(t *testing.T) {
+	pick := func(r *rand.Rand) string { return "a" }
+	r := rand.New(rand.NewSource(1))
+	if got := Glyphs(r, 3, pick); len(got) != 3 {
+		t.Fatalf("expected exactly 3 picks for 3 single-rune glyphs, got %d", len(got))
+	}
+}
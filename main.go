@@ -1,39 +1,312 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"flag"
 	"fmt"
 	"math/rand"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
+
+	"golang.org/x/term"
+
+	"github.com/ovnanova/AAAAAAAAA/glyphs"
+	"github.com/ovnanova/AAAAAAAAA/internal/rng"
+	"github.com/ovnanova/AAAAAAAAA/output"
+	"github.com/ovnanova/AAAAAAAAA/zalgo"
 )
 
-var charSet = []string{
-	"A̵̦̦̓͌͗͛̕", "A", "₳", "░A░", "A҉", "Ⱥ", "A̷", "A̲", "A̳", "A̾",
-	"A͎", "A͓̽", "𝔸", "ᴀ", "∀",
+// forceExitGrace bounds how long a second interrupt has to arrive before
+// main stops waiting for it; see main's signal handling.
+const forceExitGrace = 2 * time.Second
+
+// signalName maps the signals main listens for to the record shape's
+// documented reason strings; any other signal falls back to its os/signal
+// String() form.
+func signalName(sig os.Signal) string {
+	switch sig {
+	case syscall.SIGINT:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	default:
+		return sig.String()
+	}
+}
+
+// sampler is satisfied by both glyphs.GlyphSet and zalgo.Generator, so a
+// Generator can drive either through the same loop.
+type sampler interface {
+	SampleGlyphs(r *rand.Rand, visible int) []string
+}
+
+// tokenBucket paces emissions to roughly `rate` per second while still
+// allowing short bursts of up to `capacity` tokens to drain instantly.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	rate     float64
+	last     time.Time
+}
+
+func newTokenBucket(rate, capacity float64) *tokenBucket {
+	return &tokenBucket{
+		tokens:   capacity,
+		capacity: capacity,
+		rate:     rate,
+		last:     time.Now(),
+	}
+}
+
+// take refills the bucket based on elapsed time and reports whether a
+// token was available to consume.
+func (b *tokenBucket) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+
+	b.tokens += elapsed * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// jittered returns d randomized by up to ±pct percent, so a fixed cadence
+// doesn't read as obviously mechanical.
+func jittered(d time.Duration, pct float64, r *rand.Rand) time.Duration {
+	if pct <= 0 {
+		return d
+	}
+	spread := float64(d) * (pct / 100)
+	offset := (r.Float64()*2 - 1) * spread
+	jittered := float64(d) + offset
+	// time.Ticker.Reset panics on a non-positive duration, so a large
+	// -jitter can't be allowed to floor out at exactly 0.
+	if jittered < 1 {
+		jittered = 1
+	}
+	return time.Duration(jittered)
+}
+
+// Stats summarizes a finished run, for the shutdown report and for
+// -output=json's final "shutdown" record.
+type Stats struct {
+	Reason  string
+	Lines   int64
+	Bytes   int64
+	Runtime time.Duration
+	Seed    int64
+}
+
+// EffectiveRate returns the observed lines/sec over the run.
+func (s Stats) EffectiveRate() float64 {
+	if s.Runtime <= 0 {
+		return 0
+	}
+	return float64(s.Lines) / s.Runtime.Seconds()
+}
+
+// Generator is the pacing, sampling, and output loop: the extension point
+// the other flags (-rate, -set, -output, ...) all plug into.
+type Generator struct {
+	Rate      float64
+	Burst     float64
+	JitterPct float64
+	Length    int
+	Duration  time.Duration
+	Count     int64
+	Seed      int64
+
+	Set sampler
+	Out *output.Writer
+	R   *rand.Rand
 }
 
-func randomString() string {
-	length := rand.Intn(20) + 1
-	var sb strings.Builder
-	for i := 0; i < length; i++ {
-		sb.WriteString(charSet[rand.Intn(len(charSet))])
+// Run paces and emits lines until ctx is cancelled, -duration elapses, or
+// -count is reached, then returns Stats. The caller is responsible for
+// calling Out.Shutdown once the final reason is known: a ctx cancellation
+// resolves here to the generic "signal", since Run itself has no way to
+// learn which actual signal fired; main refines it to "SIGINT"/"SIGTERM"
+// before it reaches Out.Shutdown and the exit report.
+func (g *Generator) Run(ctx context.Context) Stats {
+	bucket := newTokenBucket(g.Rate, g.Burst)
+	interval := time.Duration(float64(time.Second) / g.Rate)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var deadline <-chan time.Time
+	if g.Duration > 0 {
+		timer := time.NewTimer(g.Duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	start := time.Now()
+	var lines, bytes int64
+	reason := ""
+
+	for reason == "" {
+		select {
+		case <-ctx.Done():
+			reason = "signal"
+		case <-deadline:
+			reason = "duration"
+		case <-ticker.C:
+			if bucket.take() {
+				n := g.Length
+				if n <= 0 {
+					n = g.R.Intn(20) + 1
+				}
+				written, _ := g.Out.WriteLine(g.R, lines, g.Set.SampleGlyphs(g.R, n))
+				bytes += int64(written)
+				lines++
+			}
+			g.Out.Flush()
+			ticker.Reset(jittered(interval, g.JitterPct, g.R))
+			if g.Count > 0 && lines >= g.Count {
+				reason = "count"
+			}
+		}
 	}
-	return sb.String()
+
+	return Stats{Reason: reason, Lines: lines, Bytes: bytes, Runtime: time.Since(start), Seed: g.Seed}
 }
 
 func main() {
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	rate := flag.Float64("rate", 10, "output rate in lines/sec")
+	burst := flag.Float64("burst", 1, "token-bucket burst capacity (lines)")
+	jitterPct := flag.Float64("jitter", 0, "± percent randomization around the tick interval")
+	duration := flag.Duration("duration", 0, "stop after this long (0 = unlimited)")
+	count := flag.Int64("count", 0, "stop after this many lines (0 = unlimited)")
+	seed := flag.Int64("seed", 0, "seed for reproducible output (0 = seed from crypto/rand)")
+	setName := flag.String("set", "zalgo", "glyph set to use: zalgo (procedural), "+strings.Join(glyphs.Names(), ", "))
+	setFile := flag.String("set-file", "", "load a glyph set from a UTF-8 file instead of a built-in -set")
+	length := flag.Int("length", 0, "visible glyphs per line (0 = random 1-20)")
+	zalgoUp := flag.Int("zalgo-up", 8, "max combining marks stacked above the base rune (0-20, -set=zalgo only)")
+	zalgoDown := flag.Int("zalgo-down", 8, "max combining marks stacked below the base rune (0-20, -set=zalgo only)")
+	zalgoMid := flag.Int("zalgo-mid", 2, "max overlay/enclosing combining marks (0-20, -set=zalgo only)")
+	zalgoCreep := flag.Float64("zalgo-creep", 0, "extra zalgo intensity gained per second since start (-set=zalgo only)")
+	outputFlag := flag.String("output", "text", "output mode: text, json, or ansi")
+	colorFlag := flag.String("color", "truecolor", "ansi color level: none, 8, 256, or truecolor (-output=ansi only)")
+	flag.Parse()
+
+	outputMode, err := output.ParseMode(*outputFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	colorLevel, err := output.ParseColor(*colorFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		colorLevel = output.ColorNone
+	}
+
+	if *rate <= 0 {
+		fmt.Fprintln(os.Stderr, "-rate must be > 0")
+		os.Exit(1)
+	}
+	if *burst < 1 {
+		*burst = 1
+	}
+
+	var set sampler
+	switch {
+	case *setFile != "":
+		loaded, err := glyphs.Load(*setFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		set = loaded
+	case *setName == "zalgo":
+		set = zalgo.New(*zalgoUp, *zalgoDown, *zalgoMid, *zalgoCreep)
+	default:
+		builtin, ok := glyphs.Builtin(*setName)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown -set %q, available: zalgo, %s\n", *setName, strings.Join(glyphs.Names(), ", "))
+			os.Exit(1)
+		}
+		set = builtin
+	}
+
+	seedUsed := *seed
+	if seedUsed == 0 {
+		seedUsed = rng.CryptoSeed()
+	}
+	r := rng.NewSource(rng.NewSeededSource(seedUsed))
+
+	w := bufio.NewWriter(os.Stdout)
+	gen := &Generator{
+		Rate:      *rate,
+		Burst:     *burst,
+		JitterPct: *jitterPct,
+		Length:    *length,
+		Duration:  *duration,
+		Count:     *count,
+		Seed:      seedUsed,
+		Set:       set,
+		Out:       output.NewWriter(w, outputMode, colorLevel, seedUsed),
+		R:         r,
+	}
+
+	// Buffered for 2 so a rapid double signal can't be dropped while
+	// we're still reading the first one off the channel.
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// signalReason names the concrete signal that triggered shutdown, so
+	// the "signal" Stats.Reason Run reports can be refined into e.g.
+	// "SIGINT" below; it's written before cancel() and only read after
+	// gen.Run(ctx) observes ctx.Done(), so the channel close ordering
+	// makes the write visible without extra synchronization.
+	var signalReason string
 
 	go func() {
-		for {
-			fmt.Println(randomString())
-			time.Sleep(100 * time.Millisecond)
+		signalReason = signalName(<-sigCh)
+		cancel()
+
+		// A second interrupt within the grace period forces an immediate
+		// exit instead of waiting for the drain below to finish.
+		select {
+		case <-sigCh:
+			fmt.Fprintln(os.Stderr, "second interrupt received, forcing exit")
+			os.Exit(130)
+		case <-time.After(forceExitGrace):
 		}
 	}()
 
-	<-sigs
+	stats := gen.Run(ctx)
+
+	reason := stats.Reason
+	if reason == "signal" && signalReason != "" {
+		reason = signalReason
+	}
+
+	written, _ := gen.Out.Shutdown(reason, stats.Lines)
+	stats.Bytes += int64(written)
+	gen.Out.Flush()
+
+	fmt.Fprintf(os.Stderr, "lines=%d bytes=%d runtime=%s rate=%.2f/s seed=%d reason=%s\n",
+		stats.Lines, stats.Bytes, stats.Runtime.Round(time.Millisecond), stats.EffectiveRate(), stats.Seed, reason)
 }
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math/rand"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ovnanova/AAAAAAAAA/output"
+)
+
+// constSampler is a minimal sampler for tests that don't care which glyphs
+// come out, only how many lines Run produces.
+type constSampler struct{}
+
+func (constSampler) SampleGlyphs(r *rand.Rand, visible int) []string {
+	return []string{"A"}
+}
+
+func newTestGenerator() *Generator {
+	bw := bufio.NewWriter(io.Discard)
+	return &Generator{
+		Rate:   1000,
+		Burst:  1000,
+		Length: 1,
+		Seed:   1,
+		Set:    constSampler{},
+		Out:    output.NewWriter(bw, output.ModeText, output.ColorNone, 1),
+		R:      rand.New(rand.NewSource(1)),
+	}
+}
+
+func TestTokenBucketAllowsBurstThenPaces(t *testing.T) {
+	b := newTokenBucket(1, 3)
+	b.last = time.Now()
+	for i := 0; i < 3; i++ {
+		if !b.take() {
+			t.Fatalf("take %d: expected a burst token to be available", i)
+		}
+	}
+	if b.take() {
+		t.Fatal("expected bucket to be empty after draining the burst capacity")
+	}
+}
+
+func TestTokenBucketRefillsOverTime(t *testing.T) {
+	b := newTokenBucket(10, 1)
+	if !b.take() {
+		t.Fatal("expected the initial token to be available")
+	}
+	if b.take() {
+		t.Fatal("expected the bucket to be empty immediately after draining it")
+	}
+	b.last = b.last.Add(-200 * time.Millisecond)
+	if !b.take() {
+		t.Fatal("expected a token to have refilled after 200ms at rate 10/s")
+	}
+}
+
+func TestJitteredStaysWithinPercentSpread(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	d := 100 * time.Millisecond
+	spread := time.Duration(float64(d) * 0.2)
+	for i := 0; i < 100; i++ {
+		got := jittered(d, 20, r)
+		if got < d-spread || got > d+spread {
+			t.Fatalf("jittered(%s, 20%%) = %s, outside ±20%% of %s", d, got, d)
+		}
+	}
+}
+
+func TestJitteredZeroPercentReturnsExact(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	d := 250 * time.Millisecond
+	if got := jittered(d, 0, r); got != d {
+		t.Fatalf("jittered(%s, 0%%) = %s, want exact %s", d, got, d)
+	}
+}
+
+func TestJitteredNeverReturnsNonPositive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	d := 1 * time.Microsecond
+	for i := 0; i < 100; i++ {
+		if got := jittered(d, 500, r); got <= 0 {
+			t.Fatalf("jittered produced non-positive duration %s, would panic Ticker.Reset", got)
+		}
+	}
+}
+
+func TestSignalNameMapsKnownSignals(t *testing.T) {
+	if got := signalName(syscall.SIGINT); got != "SIGINT" {
+		t.Fatalf("signalName(SIGINT) = %q, want %q", got, "SIGINT")
+	}
+	if got := signalName(syscall.SIGTERM); got != "SIGTERM" {
+		t.Fatalf("signalName(SIGTERM) = %q, want %q", got, "SIGTERM")
+	}
+}
+
+func TestGeneratorRunStopsAtCount(t *testing.T) {
+	gen := newTestGenerator()
+	gen.Count = 5
+
+	stats := gen.Run(context.Background())
+	if stats.Reason != "count" {
+		t.Fatalf("Reason = %q, want %q", stats.Reason, "count")
+	}
+	if stats.Lines != 5 {
+		t.Fatalf("Lines = %d, want 5", stats.Lines)
+	}
+	if stats.Bytes == 0 {
+		t.Fatal("expected Bytes to reflect written output")
+	}
+}
+
+func TestGeneratorRunStopsOnContextCancel(t *testing.T) {
+	gen := newTestGenerator()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stats := gen.Run(ctx)
+	if stats.Reason != "signal" {
+		t.Fatalf("Reason = %q, want %q", stats.Reason, "signal")
+	}
+}
+
+func TestGeneratorRunStopsAtDuration(t *testing.T) {
+	gen := newTestGenerator()
+	gen.Duration = 10 * time.Millisecond
+
+	stats := gen.Run(context.Background())
+	if stats.Reason != "duration" {
+		t.Fatalf("Reason = %q, want %q", stats.Reason, "duration")
+	}
+}
+
+func TestEffectiveRate(t *testing.T) {
+	s := Stats{Lines: 20, Runtime: 2 * time.Second}
+	if got := s.EffectiveRate(); got != 10 {
+		t.Fatalf("EffectiveRate() = %v, want 10", got)
+	}
+}
+
+func TestEffectiveRateZeroRuntime(t *testing.T) {
+	s := Stats{Lines: 20, Runtime: 0}
+	if got := s.EffectiveRate(); got != 0 {
+		t.Fatalf("EffectiveRate() = %v, want 0 for zero runtime", got)
+	}
+}
@@ -0,0 +1,161 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func newTestWriter(mode Mode, color Color, seed int64) (*Writer, *bytes.Buffer, *bufio.Writer) {
+	var buf bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+	return NewWriter(bw, mode, color, seed), &buf, bw
+}
+
+func TestParseModeAcceptsKnownValues(t *testing.T) {
+	for _, s := range []string{"text", "json", "ansi"} {
+		if _, err := ParseMode(s); err != nil {
+			t.Fatalf("ParseMode(%q) returned unexpected error: %v", s, err)
+		}
+	}
+}
+
+func TestParseModeRejectsUnknown(t *testing.T) {
+	if _, err := ParseMode("xml"); err == nil {
+		t.Fatal("expected ParseMode to reject an unknown mode")
+	}
+}
+
+func TestParseColorAcceptsKnownValues(t *testing.T) {
+	for _, s := range []string{"none", "8", "256", "truecolor"} {
+		if _, err := ParseColor(s); err != nil {
+			t.Fatalf("ParseColor(%q) returned unexpected error: %v", s, err)
+		}
+	}
+}
+
+func TestParseColorRejectsUnknown(t *testing.T) {
+	if _, err := ParseColor("16"); err == nil {
+		t.Fatal("expected ParseColor to reject an unknown color level")
+	}
+}
+
+func TestWriteLineTextModeEmitsRawJoinedGlyphs(t *testing.T) {
+	out, buf, bw := newTestWriter(ModeText, ColorNone, 0)
+	r := rand.New(rand.NewSource(1))
+	if _, err := out.WriteLine(r, 0, []string{"A", "A", "A"}); err != nil {
+		t.Fatalf("WriteLine returned error: %v", err)
+	}
+	bw.Flush()
+	if got := buf.String(); got != "AAA\n" {
+		t.Fatalf("got %q, want %q", got, "AAA\n")
+	}
+}
+
+func TestWriteLineJSONModeShapesRecord(t *testing.T) {
+	out, buf, bw := newTestWriter(ModeJSON, ColorNone, 42)
+	r := rand.New(rand.NewSource(1))
+	if _, err := out.WriteLine(r, 7, []string{"A", "A"}); err != nil {
+		t.Fatalf("WriteLine returned error: %v", err)
+	}
+	bw.Flush()
+
+	var rec record
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("output is not valid JSON: %v (%q)", err, buf.String())
+	}
+	if rec.Seq != 7 || rec.Seed != 42 || rec.Raw != "AA" || len(rec.Glyphs) != 2 {
+		t.Fatalf("unexpected record %+v", rec)
+	}
+}
+
+func TestColorizeNoneLeavesGlyphsUnescaped(t *testing.T) {
+	out, _, _ := newTestWriter(ModeANSI, ColorNone, 0)
+	r := rand.New(rand.NewSource(1))
+	got := out.colorize(r, []string{"A", "B"})
+	if got != "AB" {
+		t.Fatalf("expected unescaped glyphs under ColorNone, got %q", got)
+	}
+}
+
+func TestColorizeAppliesResetAndEscapes(t *testing.T) {
+	out, _, _ := newTestWriter(ModeANSI, Color8, 0)
+	r := rand.New(rand.NewSource(1))
+	got := out.colorize(r, []string{"A"})
+	if !strings.HasPrefix(got, "\x1b[3") {
+		t.Fatalf("expected an 8-color SGR escape before the glyph, got %q", got)
+	}
+	if !strings.HasSuffix(got, "\x1b[0m") {
+		t.Fatalf("expected a trailing reset escape, got %q", got)
+	}
+}
+
+func TestSGRMatchesColorLevel(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	out8, _, _ := newTestWriter(ModeANSI, Color8, 0)
+	if esc := out8.sgr(r); !strings.HasPrefix(esc, "\x1b[3") || strings.Contains(esc, ";") {
+		t.Fatalf("Color8 sgr() = %q, want a bare \\x1b[3Nm escape", esc)
+	}
+
+	out256, _, _ := newTestWriter(ModeANSI, Color256, 0)
+	if esc := out256.sgr(r); !strings.HasPrefix(esc, "\x1b[38;5;") {
+		t.Fatalf("Color256 sgr() = %q, want an \\x1b[38;5;Nm escape", esc)
+	}
+
+	outTrue, _, _ := newTestWriter(ModeANSI, ColorTrueColor, 0)
+	if esc := outTrue.sgr(r); !strings.HasPrefix(esc, "\x1b[38;2;") {
+		t.Fatalf("ColorTrueColor sgr() = %q, want an \\x1b[38;2;R;G;Bm escape", esc)
+	}
+
+	outNone, _, _ := newTestWriter(ModeANSI, ColorNone, 0)
+	if esc := outNone.sgr(r); esc != "" {
+		t.Fatalf("ColorNone sgr() = %q, want empty string", esc)
+	}
+}
+
+func TestShutdownOnlyWritesUnderJSONMode(t *testing.T) {
+	out, buf, bw := newTestWriter(ModeText, ColorNone, 0)
+	n, err := out.Shutdown("signal", 3)
+	if err != nil || n != 0 {
+		t.Fatalf("expected no-op Shutdown under text mode, got n=%d err=%v", n, err)
+	}
+	bw.Flush()
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output under text mode, got %q", buf.String())
+	}
+
+	jsonOut, jsonBuf, jsonBW := newTestWriter(ModeJSON, ColorNone, 0)
+	if _, err := jsonOut.Shutdown("signal", 3); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+	jsonBW.Flush()
+
+	var rec shutdownRecord
+	if err := json.Unmarshal(jsonBuf.Bytes(), &rec); err != nil {
+		t.Fatalf("shutdown output is not valid JSON: %v (%q)", err, jsonBuf.String())
+	}
+	if rec.Event != "shutdown" || rec.Reason != "signal" || rec.Total != 3 {
+		t.Fatalf("unexpected shutdown record %+v", rec)
+	}
+}
+
+func TestFlushFlushesUnderlyingWriter(t *testing.T) {
+	out, buf, _ := newTestWriter(ModeText, ColorNone, 0)
+	r := rand.New(rand.NewSource(1))
+	if _, err := out.WriteLine(r, 0, []string{"A"}); err != nil {
+		t.Fatalf("WriteLine returned error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatal("expected nothing to reach buf before Flush")
+	}
+	if err := out.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Fatal("expected WriteLine's output to reach buf after Flush")
+	}
+}
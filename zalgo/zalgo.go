@@ -0,0 +1,103 @@
+// Package zalgo procedurally stacks Unicode combining marks on a base rune,
+// rather than sampling from a fixed list of pre-baked strings.
+package zalgo
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/ovnanova/AAAAAAAAA/internal/sampling"
+)
+
+// maxIntensity is the ceiling each of -zalgo-up/-down/-mid accepts.
+const maxIntensity = 20
+
+// upMarks render above the base character, downMarks below the baseline,
+// and midMarks as overlay/enclosing strokes through it. Each is a subset of
+// the U+0300-U+036F combining diacritical marks block.
+var (
+	upMarks   = makeRange(0x0300, 0x0315)
+	downMarks = makeRange(0x0316, 0x0333)
+	midMarks  = makeRange(0x0334, 0x0338)
+)
+
+func makeRange(lo, hi rune) []rune {
+	marks := make([]rune, 0, hi-lo+1)
+	for r := lo; r <= hi; r++ {
+		marks = append(marks, r)
+	}
+	return marks
+}
+
+// Generator produces zalgo text around a base rune, with independently
+// tunable intensities for marks above, below, and through the base.
+type Generator struct {
+	Base          rune
+	Up, Down, Mid int
+	Creep         float64
+	start         time.Time
+}
+
+// New returns a Generator with up/down/mid intensities clamped to
+// [0, 20] and creep (extra intensity gained per second since start)
+// applied on top.
+func New(up, down, mid int, creep float64) *Generator {
+	return &Generator{
+		Base:  'A',
+		Up:    clamp(up),
+		Down:  clamp(down),
+		Mid:   clamp(mid),
+		Creep: creep,
+		start: time.Now(),
+	}
+}
+
+func clamp(n int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > maxIntensity {
+		return maxIntensity
+	}
+	return n
+}
+
+// intensities returns the current effective up/down/mid intensities,
+// including any creep accrued since the generator started.
+func (g *Generator) intensities() (up, down, mid int) {
+	boost := int(time.Since(g.start).Seconds() * g.Creep)
+	return clamp(g.Up + boost), clamp(g.Down + boost), clamp(g.Mid + boost)
+}
+
+// Pick generates one zalgo glyph: the base rune followed by its stacked
+// combining marks, with no intervening base characters so the marks stay
+// attached to it.
+func (g *Generator) Pick(r *rand.Rand) string {
+	up, down, mid := g.intensities()
+
+	var sb strings.Builder
+	sb.WriteRune(g.Base)
+	for i, n := 0, r.Intn(up+1); i < n; i++ {
+		sb.WriteRune(upMarks[r.Intn(len(upMarks))])
+	}
+	for i, n := 0, r.Intn(down+1); i < n; i++ {
+		sb.WriteRune(downMarks[r.Intn(len(downMarks))])
+	}
+	for i, n := 0, r.Intn(mid+1); i < n; i++ {
+		sb.WriteRune(midMarks[r.Intn(len(midMarks))])
+	}
+	return sb.String()
+}
+
+// SampleGlyphs returns the individual glyphs making up exactly visible
+// glyphs-worth of output, counted in grapheme clusters so each stacked
+// base+marks cluster counts once regardless of how many marks it carries.
+func (g *Generator) SampleGlyphs(r *rand.Rand, visible int) []string {
+	return sampling.Glyphs(r, visible, g.Pick)
+}
+
+// Sample builds a single string from SampleGlyphs.
+func (g *Generator) Sample(r *rand.Rand, visible int) string {
+	return strings.Join(g.SampleGlyphs(r, visible), "")
+}
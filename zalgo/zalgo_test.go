@@ -0,0 +1,46 @@
+package zalgo
+
+import (
+	"math/rand"
+	"testing"
+	"unicode"
+)
+
+func TestPickStartsWithBaseRune(t *testing.T) {
+	g := New(5, 5, 5, 0)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		out := []rune(g.Pick(r))
+		if out[0] != 'A' {
+			t.Fatalf("expected glyph to start with base rune, got %q", string(out))
+		}
+		for _, c := range out[1:] {
+			if !unicode.Is(unicode.Mn, c) && !unicode.Is(unicode.Me, c) {
+				t.Fatalf("expected only combining marks after base rune, got %q in %q", c, string(out))
+			}
+		}
+	}
+}
+
+func TestZeroIntensityProducesBareBase(t *testing.T) {
+	g := New(0, 0, 0, 0)
+	r := rand.New(rand.NewSource(1))
+	if got := g.Pick(r); got != "A" {
+		t.Fatalf("expected bare base rune with zero intensity, got %q", got)
+	}
+}
+
+func TestSampleCountsGraphemeClusters(t *testing.T) {
+	g := New(3, 3, 1, 0)
+	r := rand.New(rand.NewSource(1))
+	out := g.Sample(r, 6)
+	var bases int
+	for _, c := range out {
+		if c == 'A' {
+			bases++
+		}
+	}
+	if bases != 6 {
+		t.Fatalf("expected 6 visible glyphs, got %d bases in %q", bases, out)
+	}
+}